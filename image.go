@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMaxImageDim  = 800
+	defaultImageQuality = 85
+)
+
+// archivedImage is what syncSource needs to rewrite the front template's <img>
+// tag to point at the archived copy.
+type archivedImage struct {
+	Filename string
+}
+
+// archiveImage downloads imageURL, optionally shrinks/recompresses it (in the
+// spirit of what hugo-micropub does before uploading) so Anki's media folder
+// doesn't balloon with full-resolution Hatena thumbnails, and stores it via
+// AnkiConnect's storeMediaFile under a content-addressed name so re-running
+// the sync against the same image is a no-op.
+func archiveImage(ctx context.Context, cl *http.Client, a Anki, imageURL string, maxDim, quality int) (*archivedImage, error) {
+	if imageURL == "" {
+		return nil, xerrors.New("archiveImage: empty image URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("archiveImage NewRequest: %w", err)
+	}
+
+	resp, err := cl.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("archiveImage Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, xerrors.Errorf("archiveImage Non-OK HTTP status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("archiveImage ReadAll: %w", err)
+	}
+
+	data, ext := compressImage(data, maxDim, quality)
+
+	sum := sha256.Sum256(data)
+	filename := fmt.Sprintf("hatena-%s%s", hex.EncodeToString(sum[:]), ext)
+
+	r, err := a.StoreMediaFile(ctx, filename, data)
+	if err != nil {
+		return nil, xerrors.Errorf("archiveImage StoreMediaFile: %w", err)
+	}
+	if r.Error != "" {
+		return nil, xerrors.Errorf("archiveImage StoreMediaFile: %s", r.Error)
+	}
+
+	return &archivedImage{Filename: filename}, nil
+}
+
+// compressImage shrinks img down to fit within maxDim on its longest side and
+// re-encodes JPEGs at quality; PNGs are resized but kept lossless. Anything
+// it can't decode (or fails to re-encode) is returned unchanged as a JPEG.
+func compressImage(data []byte, maxDim, quality int) ([]byte, string) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return data, ".jpg"
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > maxDim || h > maxDim {
+		if w > h {
+			h = h * maxDim / w
+			w = maxDim
+		} else {
+			w = w * maxDim / h
+			h = maxDim
+		}
+
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+		src = dst
+	}
+
+	var buf bytes.Buffer
+	if format == "png" {
+		if err := png.Encode(&buf, src); err != nil {
+			return data, ".jpg"
+		}
+		return buf.Bytes(), ".png"
+	}
+
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: quality}); err != nil {
+		return data, ".jpg"
+	}
+	return buf.Bytes(), ".jpg"
+}
@@ -1,13 +1,11 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -19,26 +17,76 @@ import (
 
 	"github.com/cockroachdb/pebble"
 	"github.com/mmcdole/gofeed"
-	ext "github.com/mmcdole/gofeed/extensions"
+
+	"github.com/ikeikeikeike/anki-breakdown-hatena-rss/pkg/ankiconnect"
 )
 
 type args struct {
-	URL  string
-	Deck string
+	URL          string
+	Deck         string
+	OPML         string
+	DumpOPML     string
+	ImageMaxDim  int
+	ImageQuality int
+	Template     string
+
+	Source         string
+	ShioriURL      string
+	ShioriUsername string
+	ShioriPassword string
+	ShioriToken    string
 }
 
+const (
+	sourceHatena = "hatena"
+	sourceShiori = "shiori"
+)
+
 func parseArgs() (*args, error) {
 	a := &args{}
 
 	flag.StringVar(&a.URL, "url", "", "e.g. https://b.hatena.ne.jp/ikeikeikeike/bookmark.rss")
 	flag.StringVar(&a.Deck, "deck", "Hatena", "Note's Deckname")
+	flag.StringVar(&a.OPML, "opml", "", "path to an OPML file listing multiple feeds to sync, overrides -url/-deck")
+	flag.StringVar(&a.DumpOPML, "dump-opml", "", "write the feeds currently tracked in pebble out to this OPML path and exit")
+	flag.IntVar(&a.ImageMaxDim, "image-max-dim", defaultMaxImageDim, "shrink archived thumbnails so neither side exceeds this many pixels")
+	flag.IntVar(&a.ImageQuality, "image-quality", defaultImageQuality, "JPEG quality (1-100) used when re-encoding archived thumbnails")
+	flag.StringVar(&a.Template, "template", "", "path to a Lua script exposing render_front(item)/render_back(item) (default: embedded script reproducing today's output)")
+	flag.StringVar(&a.Source, "source", sourceHatena, "bookmark source: hatena or shiori")
+	flag.StringVar(&a.ShioriURL, "shiori-url", "", "e.g. http://localhost:8080, required when -source=shiori")
+	flag.StringVar(&a.ShioriUsername, "shiori-username", "", "Shiori login username, unless -shiori-token is set")
+	flag.StringVar(&a.ShioriPassword, "shiori-password", "", "Shiori login password, unless -shiori-token is set")
+	flag.StringVar(&a.ShioriToken, "shiori-token", "", "Shiori session token, skips the username/password login")
 	flag.Parse()
 
+	if a.DumpOPML != "" {
+		return a, nil
+	}
+
+	if a.Source != sourceHatena && a.Source != sourceShiori {
+		return nil, xerrors.Errorf("unknown -source %q, expected %q or %q", a.Source, sourceHatena, sourceShiori)
+	}
+
 	seen := make(map[string]bool)
 	flag.Visit(func(f *flag.Flag) { seen[f.Name] = true })
-	for _, r := range []string{"url"} {
-		if !seen[r] {
-			return nil, xerrors.Errorf("missing required -%s argument/flag", r)
+
+	if a.Source == sourceShiori {
+		for _, r := range []string{"shiori-url"} {
+			if !seen[r] {
+				return nil, xerrors.Errorf("missing required -%s argument/flag", r)
+			}
+		}
+		if a.ShioriToken == "" && (a.ShioriUsername == "" || a.ShioriPassword == "") {
+			return nil, xerrors.Errorf("-source=shiori requires either -shiori-token or both -shiori-username and -shiori-password")
+		}
+		return a, nil
+	}
+
+	if a.OPML == "" {
+		for _, r := range []string{"url"} {
+			if !seen[r] {
+				return nil, xerrors.Errorf("missing required -%s argument/flag", r)
+			}
 		}
 	}
 
@@ -51,67 +99,202 @@ func main() {
 	if err != nil {
 		log.Panic(err) // Panic is useful for the simply script
 	}
-	fp := gofeed.NewParser()
-	fp.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
 
-	feed, err := fp.ParseURL(args.URL)
+	db, err := mewPebble()
 	if err != nil {
 		log.Panic(err) // Panic is useful for the simply script
 	}
-	db, err := mewPebble()
+	defer func() { _ = db.Close() }()
+
+	if args.DumpOPML != "" {
+		feeds, err := trackedFeeds(db)
+		if err != nil {
+			log.Panic(err) // Panic is useful for the simply script
+		}
+		if err := dumpOPML(args.DumpOPML, feeds); err != nil {
+			log.Panic(err) // Panic is useful for the simply script
+		}
+		log.Printf("OK: dumped %d feed(s) to %s\n", len(feeds), args.DumpOPML)
+		return
+	}
+
+	tmpl, err := loadCardTemplate(args.Template)
 	if err != nil {
 		log.Panic(err) // Panic is useful for the simply script
 	}
-	defer func() { _ = db.Close() }()
+	defer tmpl.Close()
 
 	ctx := context.Background()
+	cl := newHTTPClient()
+	a := newAnki(cl)
+
+	if args.Source == sourceShiori {
+		fc := feedConfig{URL: args.ShioriURL, Deck: args.Deck}
+		src := newShioriSource(cl, shioriConfig{
+			BaseURL:  args.ShioriURL,
+			Username: args.ShioriUsername,
+			Password: args.ShioriPassword,
+			Token:    args.ShioriToken,
+		})
+		syncSource(ctx, db, cl, a, tmpl, fc, src, args.ImageMaxDim, args.ImageQuality)
+		return
+	}
+
+	var feeds []feedConfig
+	if args.OPML != "" {
+		feeds, err = loadOPML(args.OPML)
+		if err != nil {
+			log.Panic(err) // Panic is useful for the simply script
+		}
+	} else {
+		feeds = []feedConfig{{URL: args.URL, Deck: args.Deck}}
+	}
+
+	fp := gofeed.NewParser()
+	fp.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"
 
-	a := newAnki()
-	for _, item := range feed.Items {
-		key := pebbleKey(args.URL, args.Deck, item.Link)
+	for _, fc := range feeds {
+		if err := trackFeed(db, fc); err != nil {
+			log.Printf("Err DB Write feed: %+v\n", err)
+		}
+
+		syncSource(ctx, db, cl, a, tmpl, fc, newHatenaSource(cl, fp, fc), args.ImageMaxDim, args.ImageQuality)
+	}
+}
+
+// syncSource pulls every Item out of src and walks it through the existing
+// pebble dedup / AddNote pipeline, tagging notes with fc.Tags in addition to
+// whatever categories the item itself carries.
+func syncSource(ctx context.Context, db *pebble.DB, cl *http.Client, a Anki, tmpl *cardTemplate, fc feedConfig, src Source, imageMaxDim, imageQuality int) {
+	if err := a.CreateDeck(ctx, fc.Deck); err != nil {
+		log.Printf("Err CreateDeck %s: %+v\n", fc.Deck, err)
+	}
+
+	items, err := src.Items(ctx)
+	if err != nil {
+		log.Printf("Err %s Items: %+v\n", src.Name(), err)
+		return
+	}
+
+	for _, item := range items {
+		key := pebbleKey(src.Name(), fc.URL, fc.Deck, item.Link)
 
 		value, closer, err := db.Get(key)
 		if err != nil && !xerrors.Is(err, pebble.ErrNotFound) {
 			log.Printf("Err DB Get: %+v\n", err)
 			continue
 		}
+		var rec syncRecord
+		haveRecord := len(value) != 0
+		if haveRecord {
+			if err := json.Unmarshal(value, &rec); err != nil {
+				log.Printf("Err Unmarshal syncRecord %q: %+v\n", value, err)
+				haveRecord = false
+			}
+		}
 		if closer != nil {
 			if err := closer.Close(); err != nil {
 				log.Printf("Err DB Closer: %+v\n", err)
 				continue
 			}
 		}
-		if len(value) != 0 {
-			log.Printf("NG Dup by: %s:%s:%s\n", args.URL, args.Deck, item.Link)
+
+		if haveRecord {
+			// Known URL: refresh the bookmark count instead of skipping, so
+			// a popular link's card keeps reflecting how many people
+			// bookmarked it. The thumbnail was already archived the first
+			// time this item was seen, so reuse that filename rather than
+			// re-downloading/re-uploading it on every run.
+			imgSrc := item.Image
+			if rec.Image != "" {
+				imgSrc = rec.Image
+			}
+
+			front, err := tmpl.RenderFront(&templateItem{
+				Title:         item.Title,
+				Link:          item.Link,
+				ImageURL:      imgSrc,
+				Categories:    item.Categories,
+				Author:        item.Author,
+				Description:   item.Description,
+				Content:       item.Content,
+				Published:     item.Published,
+				BookmarkCount: item.Extras["bookmark_count"],
+			})
+			if err != nil {
+				log.Printf("Err RenderFront: %+v\n", err)
+				continue
+			}
+
+			if err := a.UpdateNoteFields(ctx, rec.NoteID, map[string]string{"Front": front}); err != nil {
+				log.Printf("Err UpdateNoteFields %d: %+v\n", rec.NoteID, err)
+				continue
+			}
+			log.Printf("OK refreshed: %d\n", rec.NoteID)
+			continue
+		}
+
+		// Second-line dedup independent of pebble: catches notes this tool
+		// already added to this deck whose pebble record is missing (a
+		// pebble cache that was reset, or another source's sync) before
+		// falling through to AddNote. Notes added outside this tool carry
+		// no marker and are not found here. It
+		// matches on sourceLinkMarker(item.Link), a hidden comment appended
+		// to Back, rather than on the link as a bare substring of the
+		// rendered front: the front also embeds the bookmark count (which
+		// drifts over time), and an unanchored substring match on the link
+		// itself would let e.g. .../entry/1 wrongly match a note for
+		// .../entry/10. The marker's delimiters anchor both ends so one
+		// link can never match another's.
+		if ids, err := a.FindNotes(ctx, fmt.Sprintf("deck:%s Back:%s", quoteAnkiQuery(fc.Deck), sourceLinkQuery(item.Link))); err != nil {
+			log.Printf("Err FindNotes: %+v\n", err)
+		} else if len(ids) > 0 {
+			log.Printf("NG Dup by FindNotes: %s:%s:%s\n", fc.URL, fc.Deck, item.Link)
+			if err := setSyncRecord(db, key, syncRecord{NoteID: ids[0]}); err != nil {
+				log.Printf("Err DB Write: %+v\n", err)
+			}
+			continue
+		}
+
+		// Archive the thumbnail into Anki's media folder so the card
+		// doesn't rot when Hatena expires the original asset; fall back to
+		// hotlinking the source URL if the download/upload fails.
+		imgSrc := item.Image
+		imageFilename := ""
+		if archived, err := archiveImage(ctx, cl, a, item.Image, imageMaxDim, imageQuality); err != nil {
+			log.Printf("Err archiveImage %s: %+v\n", item.Image, err)
+		} else {
+			imgSrc = archived.Filename
+			imageFilename = archived.Filename
+		}
+
+		ti := &templateItem{
+			Title:         item.Title,
+			Link:          item.Link,
+			ImageURL:      imgSrc,
+			Categories:    item.Categories,
+			Author:        item.Author,
+			Description:   item.Description,
+			Content:       item.Content,
+			Published:     item.Published,
+			BookmarkCount: item.Extras["bookmark_count"],
+		}
+
+		front, err := tmpl.RenderFront(ti)
+		if err != nil {
+			log.Printf("Err RenderFront: %+v\n", err)
+			continue
+		}
+		back, err := tmpl.RenderBack(ti)
+		if err != nil {
+			log.Printf("Err RenderBack: %+v\n", err)
 			continue
 		}
+		back += sourceLinkMarker(item.Link)
+
+		tags := append(append([]string{}, item.Categories...), fc.Tags...)
 
-		front := fmt.Sprintf(`
-<p>Break it down?</p>
-<hr />
-<br />
-
-<img src="%s" />
-<p>%s</p>%s
-<p>%s</p>
-<br />
-<div style="text-align: left;">
-	<p>Bookmark: %s Users</p>
-	<p>Date: %s</p>
-  <p>%s: %s</p>
-</div>
-`,
-			item.Image.URL,
-			item.Title,
-			strings.Join(item.Categories, " "),
-			item.Link,
-			safeGetBookmarkCount(item.Extensions),
-			item.PublishedParsed.In(time.Local).Format("2006-01-02 15:04:05"),
-			item.Author.Name,
-			item.Description,
-		)
-
-		r, err := a.AddNote(ctx, front, item.Content, args.Deck, item.Categories)
+		r, err := a.AddNote(ctx, front, back, fc.Deck, tags)
 		if err != nil {
 			log.Printf("Err: %+v\n", err)
 			continue
@@ -121,7 +304,7 @@ func main() {
 			continue
 		}
 
-		if err := db.Set(key, []byte(fmt.Sprint(r.Result)), pebble.Sync); err != nil {
+		if err := setSyncRecord(db, key, syncRecord{NoteID: r.Result, Image: imageFilename}); err != nil {
 			log.Printf("Err DB Write: %+v\n", err)
 			continue
 		}
@@ -131,183 +314,198 @@ func main() {
 	}
 }
 
-func newAnki() Anki {
-	cl := &http.Client{
+// syncRecord is what's stored in pebble per item: the note AddNote (or
+// FindNotes) resolved to, plus the filename of the thumbnail archived for
+// it, if any, so a later refresh doesn't need to re-archive the image.
+type syncRecord struct {
+	NoteID int64  `json:"noteId"`
+	Image  string `json:"image,omitempty"`
+}
+
+func setSyncRecord(db *pebble.DB, key []byte, rec syncRecord) error {
+	value, err := json.Marshal(rec)
+	if err != nil {
+		return xerrors.Errorf("setSyncRecord Marshal: %w", err)
+	}
+
+	return db.Set(key, value, pebble.Sync)
+}
+
+// quoteAnkiQuery quotes s for use as an Anki search term (e.g.
+// `deck:"X" Front:"..."`), escaping embedded quotes/backslashes.
+func quoteAnkiQuery(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// sourceLinkMarker renders link as a hidden HTML comment, appended to every
+// new note's Back field so it can later be looked up by exact link rather
+// than by an unanchored substring of the rendered card.
+func sourceLinkMarker(link string) string {
+	return "<!--source-link:" + link + ":end-->"
+}
+
+// sourceLinkQuery builds the Anki search term that matches a note carrying
+// sourceLinkMarker(link). The marker's "source-link:" prefix and ":end-->"
+// suffix anchor both ends of link within the wildcard match, so e.g.
+// .../entry/1 can't match a note whose link is .../entry/10, and
+// https://example.com can't match https://example.com/post.
+// The marker's own "*" and "_" are escaped so Anki doesn't treat them as
+// wildcards.
+func sourceLinkQuery(link string) string {
+	m := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `*`, `\*`, `_`, `\_`).Replace(sourceLinkMarker(link))
+	return `"*` + m + `*"`
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
 		Transport: &http.Transport{
 			// TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 			// TLSHandshakeTimeout: 60 * time.Second,
 		},
 	}
-	return &anki{
-		cl:   cl,
-		host: "http://127.0.0.1:8765", // Addon: https://foosoft.net/projects/anki-connect/
-	}
+}
+
+// newAnki wraps an ankiconnect.Client as the thin Anki facade the rest of
+// this binary talks to, so its own behavior doesn't depend on the shape of
+// the AnkiConnect RPC surface.
+func newAnki(cl *http.Client) Anki {
+	return &anki{c: ankiconnect.New(cl, "http://127.0.0.1:8765")} // Addon: https://foosoft.net/projects/anki-connect/
 }
 
 type (
 	// Anki core function
 	Anki interface {
 		AddNote(ctx context.Context, front, back, deck string, tags []string) (*addNoteResult, error)
+		StoreMediaFile(ctx context.Context, filename string, data []byte) (*storeMediaResult, error)
+		CreateDeck(ctx context.Context, deck string) error
+		FindNotes(ctx context.Context, query string) ([]int64, error)
+		UpdateNoteFields(ctx context.Context, noteID int64, fields map[string]string) error
 	}
 
 	anki struct {
-		cl   *http.Client
-		host string
+		c *ankiconnect.Client
 	}
 
 	addNoteResult struct {
 		Result int64  `json:"result"`
 		Error  string `json:"error"`
 	}
+
+	storeMediaResult struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
 )
 
 func (a *anki) AddNote(ctx context.Context, front, back, deck string, tags []string) (*addNoteResult, error) {
-	name := "addNote"
-
-	data := addNoteData{
-		Action:  name,
-		Version: 6,
-		Params: addNoteParams{
-			Note: addInsideNote{
-				DeckName:  deck,
-				ModelName: "Basic",
-				Fields: addNoteFields{
-					Front: front,
-					Back:  back,
-				},
-				Options: addNoteOptions{
-					AllowDuplicate: false,
-					DuplicateScope: "deck",
-					DuplicateScopeOptions: addNoteDuplicateScopeOptions{
-						DeckName:       deck,
-						CheckChildren:  false,
-						CheckAllModels: false,
-					},
-				},
-				Tags:    tags,
-				Picture: []addNotePicture{
-					// {
-					// 	URL:      "https://example.com/image.jpg",
-					// 	Filename: "image.jpg",
-					// 	SkipHash: "8d6e4646dfae812bf39651b59d7429ce",
-					// 	Fields:   []string{"Back"}, // or Front
-					// },
-				},
+	id, err := a.c.AddNote(ctx, ankiconnect.Note{
+		DeckName:  deck,
+		ModelName: "Basic",
+		Fields: map[string]string{
+			"Front": front,
+			"Back":  back,
+		},
+		Options: ankiconnect.NoteOptions{
+			AllowDuplicate: false,
+			DuplicateScope: "deck",
+			DuplicateScopeOptions: ankiconnect.DuplicateScopeOptions{
+				DeckName:       deck,
+				CheckChildren:  false,
+				CheckAllModels: false,
 			},
 		},
-	}
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("%s json.Marshal: %w", name, err)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, a.host, bytes.NewBuffer(jsonData))
+		Tags: tags,
+	})
 	if err != nil {
-		return nil, xerrors.Errorf("%s NewRequest: %w", name, err)
+		return &addNoteResult{Error: err.Error()}, nil
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := a.cl.Do(req)
-	if err != nil {
-		return nil, xerrors.Errorf("%s request.Do: %w", name, err)
-	}
-	defer resp.Body.Close()
+	return &addNoteResult{Result: id}, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// StoreMediaFile uploads data into Anki's media folder under filename.
+func (a *anki) StoreMediaFile(ctx context.Context, filename string, data []byte) (*storeMediaResult, error) {
+	stored, err := a.c.StoreMediaFile(ctx, filename, data)
 	if err != nil {
-		return nil, xerrors.Errorf("%s ReadAll: %w", name, err)
-	}
-	if resp.StatusCode >= http.StatusInternalServerError {
-		return nil, xerrors.Errorf("%s Non-OK HTTP status %d: %s", name, resp.StatusCode, body)
+		return &storeMediaResult{Error: err.Error()}, nil
 	}
-	if resp.StatusCode >= http.StatusBadRequest {
-		return nil, xerrors.Errorf("%s Non-OK HTTP status %d: %s: %w", name, resp.StatusCode, body, ErrHTTP400)
-	}
-	// if resp.StatusCode != http.StatusCreated {
-	// 	return false, xerrors.Errorf("%s Non-OK HTTP status %d: %s: %w", name, resp.StatusCode, body, errs.ErrGRPCInvalidArgument)
-	// }
 
-	r := &addNoteResult{}
-	if err := json.Unmarshal(body, r); err != nil {
-		return nil, xerrors.Errorf("%s Unmarshal: %w", name, err)
-	}
+	return &storeMediaResult{Result: stored}, nil
+}
 
-	return r, nil
+// CreateDeck creates deck if it doesn't already exist.
+func (a *anki) CreateDeck(ctx context.Context, deck string) error {
+	_, err := a.c.CreateDeck(ctx, deck)
+	return err
 }
 
-// safeGetBookmarkCount safely retrieves the bookmark count value from the nested map structure.
-func safeGetBookmarkCount(extensions ext.Extensions) string {
-	if hatena, ok := extensions["hatena"]; ok {
-		if bookmarkcount, ok := hatena["bookmarkcount"]; ok {
-			if len(bookmarkcount) > 0 {
-				return bookmarkcount[0].Value
-			}
-		}
-	}
+// FindNotes runs an Anki search query and returns the matching note IDs.
+func (a *anki) FindNotes(ctx context.Context, query string) ([]int64, error) {
+	return a.c.FindNotes(ctx, query)
+}
 
-	return ""
+// UpdateNoteFields overwrites the given fields of an existing note.
+func (a *anki) UpdateNoteFields(ctx context.Context, noteID int64, fields map[string]string) error {
+	return a.c.UpdateNoteFields(ctx, noteID, fields)
 }
 
-var (
-	// ErrHTTP400 uses as 400 BadRequest
-	ErrHTTP400 = xerrors.New(http.StatusText(http.StatusBadRequest))
-)
+// pebbleKey concatenates the input strings with a colon separator,
+// computes the SHA-256 hash of the resulting string, and returns
+// the hash as a byte slice.
+func pebbleKey(keys ...string) []byte {
+	// Concatenate the input strings with a colon separator
+	concatenated := strings.Join(keys, ":")
+	// Compute the SHA-256 hash of the concatenated string
+	hash := sha256.Sum256([]byte(concatenated))
+	// Return the hash as a byte slice
+	return hash[:]
+}
 
-type (
-	addNoteFields struct {
-		Front string `json:"Front"`
-		Back  string `json:"Back"`
-	}
+// feedPrefix namespaces the tracked-feed entries so they can be prefix-scanned
+// by trackedFeeds without colliding with the hashed note-dedup keys.
+const feedPrefix = "feed:"
 
-	addNoteDuplicateScopeOptions struct {
-		DeckName       string `json:"deckName"`
-		CheckChildren  bool   `json:"checkChildren"`
-		CheckAllModels bool   `json:"checkAllModels"`
+// trackFeed records fc in pebble so a later -dump-opml run can reconstruct
+// the full set of feeds this cache has ever synced.
+func trackFeed(db *pebble.DB, fc feedConfig) error {
+	value, err := json.Marshal(fc)
+	if err != nil {
+		return xerrors.Errorf("trackFeed Marshal: %w", err)
 	}
 
-	addNoteOptions struct {
-		AllowDuplicate        bool                         `json:"allowDuplicate"`
-		DuplicateScope        string                       `json:"duplicateScope"`
-		DuplicateScopeOptions addNoteDuplicateScopeOptions `json:"duplicateScopeOptions"`
+	if err := db.Set([]byte(feedPrefix+fc.URL), value, pebble.Sync); err != nil {
+		return xerrors.Errorf("trackFeed Set: %w", err)
 	}
 
-	addNotePicture struct {
-		URL      string   `json:"url"`
-		Filename string   `json:"filename"`
-		SkipHash string   `json:"skipHash"`
-		Fields   []string `json:"fields"`
-	}
+	return nil
+}
 
-	addInsideNote struct {
-		DeckName  string           `json:"deckName"`
-		ModelName string           `json:"modelName"`
-		Fields    addNoteFields    `json:"fields"`
-		Options   addNoteOptions   `json:"options"`
-		Tags      []string         `json:"tags"`
-		Picture   []addNotePicture `json:"picture"`
+// trackedFeeds returns every feed previously recorded by trackFeed.
+func trackedFeeds(db *pebble.DB) ([]feedConfig, error) {
+	iter, err := db.NewIter(&pebble.IterOptions{
+		LowerBound: []byte(feedPrefix),
+		UpperBound: []byte(feedPrefix + "\xff"),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("trackedFeeds NewIter: %w", err)
 	}
+	defer iter.Close()
 
-	addNoteParams struct {
-		Note addInsideNote `json:"note"`
+	var feeds []feedConfig
+	for iter.First(); iter.Valid(); iter.Next() {
+		var fc feedConfig
+		if err := json.Unmarshal(iter.Value(), &fc); err != nil {
+			return nil, xerrors.Errorf("trackedFeeds Unmarshal: %w", err)
+		}
+		feeds = append(feeds, fc)
 	}
-
-	addNoteData struct {
-		Action  string        `json:"action"`
-		Version int           `json:"version"`
-		Params  addNoteParams `json:"params"`
+	if err := iter.Error(); err != nil {
+		return nil, xerrors.Errorf("trackedFeeds Iter: %w", err)
 	}
-)
 
-// pebbleKey concatenates the input strings with a colon separator,
-// computes the SHA-256 hash of the resulting string, and returns
-// the hash as a byte slice.
-func pebbleKey(keys ...string) []byte {
-	// Concatenate the input strings with a colon separator
-	concatenated := strings.Join(keys, ":")
-	// Compute the SHA-256 hash of the concatenated string
-	hash := sha256.Sum256([]byte(concatenated))
-	// Return the hash as a byte slice
-	return hash[:]
+	return feeds, nil
 }
 
 func mewPebble() (*pebble.DB, error) {
@@ -0,0 +1,29 @@
+package ankiconnect
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DeckNames returns the names of every deck in the Anki collection.
+func (c *Client) DeckNames(ctx context.Context) ([]string, error) {
+	return Invoke[[]string](ctx, c, "deckNames", nil)
+}
+
+// CreateDeck creates deck if it doesn't already exist and returns its deck
+// ID. AnkiConnect treats this as a no-op when the deck is already there, so
+// callers can call it unconditionally before inserting notes.
+func (c *Client) CreateDeck(ctx context.Context, deck string) (int64, error) {
+	return Invoke[int64](ctx, c, "createDeck", map[string]string{"deck": deck})
+}
+
+// ModelNames returns the names of every note type in the Anki collection.
+func (c *Client) ModelNames(ctx context.Context) ([]string, error) {
+	return Invoke[[]string](ctx, c, "modelNames", nil)
+}
+
+// Sync triggers an AnkiWeb sync, same as clicking the sync button in Anki.
+func (c *Client) Sync(ctx context.Context) error {
+	_, err := Invoke[json.RawMessage](ctx, c, "sync", nil)
+	return err
+}
@@ -0,0 +1,22 @@
+package ankiconnect
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// StoreMediaFile uploads data into Anki's media folder under filename,
+// base64-encoding the payload as AnkiConnect's storeMediaFile action
+// requires, and returns the filename actually used.
+func (c *Client) StoreMediaFile(ctx context.Context, filename string, data []byte) (string, error) {
+	return Invoke[string](ctx, c, "storeMediaFile", map[string]string{
+		"filename": filename,
+		"data":     base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// RetrieveMediaFile returns the base64-encoded contents of filename from
+// Anki's media folder.
+func (c *Client) RetrieveMediaFile(ctx context.Context, filename string) (string, error) {
+	return Invoke[string](ctx, c, "retrieveMediaFile", map[string]string{"filename": filename})
+}
@@ -0,0 +1,87 @@
+package ankiconnect
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type (
+	// NoteOptions mirrors AnkiConnect's note.options object.
+	NoteOptions struct {
+		AllowDuplicate        bool                  `json:"allowDuplicate"`
+		DuplicateScope        string                `json:"duplicateScope"`
+		DuplicateScopeOptions DuplicateScopeOptions `json:"duplicateScopeOptions"`
+	}
+
+	// DuplicateScopeOptions mirrors AnkiConnect's duplicateScopeOptions object.
+	DuplicateScopeOptions struct {
+		DeckName       string `json:"deckName"`
+		CheckChildren  bool   `json:"checkChildren"`
+		CheckAllModels bool   `json:"checkAllModels"`
+	}
+
+	// Picture mirrors one entry of AnkiConnect's note.picture array.
+	Picture struct {
+		URL      string   `json:"url,omitempty"`
+		Filename string   `json:"filename,omitempty"`
+		SkipHash string   `json:"skipHash,omitempty"`
+		Fields   []string `json:"fields"`
+	}
+
+	// Note mirrors AnkiConnect's addNote/note param object.
+	Note struct {
+		DeckName  string            `json:"deckName"`
+		ModelName string            `json:"modelName"`
+		Fields    map[string]string `json:"fields"`
+		Options   NoteOptions       `json:"options"`
+		Tags      []string          `json:"tags"`
+		Picture   []Picture         `json:"picture,omitempty"`
+	}
+
+	// NoteField is one entry of notesInfo's per-note fields map.
+	NoteField struct {
+		Value string `json:"value"`
+		Order int    `json:"order"`
+	}
+
+	// NoteInfo mirrors one entry of AnkiConnect's notesInfo result array.
+	NoteInfo struct {
+		NoteID    int64                `json:"noteId"`
+		ModelName string               `json:"modelName"`
+		Tags      []string             `json:"tags"`
+		Fields    map[string]NoteField `json:"fields"`
+	}
+)
+
+// AddNote creates note and returns its note ID.
+func (c *Client) AddNote(ctx context.Context, note Note) (int64, error) {
+	return Invoke[int64](ctx, c, "addNote", map[string]any{"note": note})
+}
+
+// FindNotes returns the note IDs matching an Anki search query.
+func (c *Client) FindNotes(ctx context.Context, query string) ([]int64, error) {
+	return Invoke[[]int64](ctx, c, "findNotes", map[string]string{"query": query})
+}
+
+// NotesInfo returns the fields/tags/model for each of the given note IDs.
+func (c *Client) NotesInfo(ctx context.Context, notes []int64) ([]NoteInfo, error) {
+	return Invoke[[]NoteInfo](ctx, c, "notesInfo", map[string][]int64{"notes": notes})
+}
+
+// UpdateNoteFields overwrites the given fields of an existing note, leaving
+// every other field untouched.
+func (c *Client) UpdateNoteFields(ctx context.Context, noteID int64, fields map[string]string) error {
+	_, err := Invoke[json.RawMessage](ctx, c, "updateNoteFields", map[string]any{
+		"note": map[string]any{
+			"id":     noteID,
+			"fields": fields,
+		},
+	})
+	return err
+}
+
+// DeleteNotes permanently deletes the given notes.
+func (c *Client) DeleteNotes(ctx context.Context, notes []int64) error {
+	_, err := Invoke[json.RawMessage](ctx, c, "deleteNotes", map[string][]int64{"notes": notes})
+	return err
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// shioriConfig holds the connection details for one Shiori instance.
+type shioriConfig struct {
+	BaseURL  string
+	Username string
+	Password string
+	Token    string
+}
+
+// shioriSource talks to a Shiori (github.com/go-shiori/shiori) instance over
+// its REST API and maps its bookmarks into the normalized Item shape.
+type shioriSource struct {
+	cl    *http.Client
+	cfg   shioriConfig
+	token string
+}
+
+func newShioriSource(cl *http.Client, cfg shioriConfig) *shioriSource {
+	return &shioriSource{cl: cl, cfg: cfg, token: cfg.Token}
+}
+
+func (s *shioriSource) Name() string { return "shiori" }
+
+func (s *shioriSource) Items(ctx context.Context) ([]Item, error) {
+	if err := s.ensureAuth(ctx); err != nil {
+		return nil, xerrors.Errorf("shiori auth: %w", err)
+	}
+
+	var items []Item
+	for page := 1; ; page++ {
+		bookmarks, hasNext, err := s.fetchPage(ctx, page)
+		if err != nil {
+			return nil, xerrors.Errorf("shiori fetchPage %d: %w", page, err)
+		}
+
+		for _, bm := range bookmarks {
+			items = append(items, bm.toItem())
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// ensureAuth logs into Shiori with user/pass unless a token was already
+// supplied via -shiori-token.
+func (s *shioriSource) ensureAuth(ctx context.Context) error {
+	if s.token != "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"username":         s.cfg.Username,
+		"password":         s.cfg.Password,
+		"remember_session": true,
+	})
+	if err != nil {
+		return xerrors.Errorf("Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.BaseURL+"/api/login", bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.cl.Do(req)
+	if err != nil {
+		return xerrors.Errorf("Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return xerrors.Errorf("Non-OK HTTP status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Session string `json:"session"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return xerrors.Errorf("Decode: %w", err)
+	}
+
+	s.token = out.Session
+	return nil
+}
+
+// fetchPage returns one page of /api/bookmarks and whether a further page
+// is available.
+func (s *shioriSource) fetchPage(ctx context.Context, page int) ([]shioriBookmark, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/bookmarks?page=%d", s.cfg.BaseURL, page), nil)
+	if err != nil {
+		return nil, false, xerrors.Errorf("NewRequest: %w", err)
+	}
+	req.Header.Set("X-Session-Id", s.token)
+
+	resp, err := s.cl.Do(req)
+	if err != nil {
+		return nil, false, xerrors.Errorf("Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, false, xerrors.Errorf("Non-OK HTTP status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Bookmarks []shioriBookmark `json:"bookmarks"`
+		MaxPage   int              `json:"maxPage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, false, xerrors.Errorf("Decode: %w", err)
+	}
+
+	return out.Bookmarks, page < out.MaxPage, nil
+}
+
+type shioriTag struct {
+	Name string `json:"name"`
+}
+
+// shioriBookmark mirrors one entry of Shiori's /api/bookmarks response.
+type shioriBookmark struct {
+	ID       int64       `json:"id"`
+	URL      string      `json:"url"`
+	Title    string      `json:"title"`
+	Excerpt  string      `json:"excerpt"`
+	Content  string      `json:"content"`
+	Author   string      `json:"author"`
+	ImageURL string      `json:"imageURL"`
+	Tags     []shioriTag `json:"tags"`
+	Modified string      `json:"modified"`
+}
+
+func (bm shioriBookmark) toItem() Item {
+	tags := make([]string, 0, len(bm.Tags))
+	for _, t := range bm.Tags {
+		tags = append(tags, t.Name)
+	}
+
+	published, _ := time.ParseInLocation("2006-01-02 15:04:05", bm.Modified, time.Local)
+
+	return Item{
+		Title:       bm.Title,
+		Link:        bm.URL,
+		Image:       bm.ImageURL,
+		Categories:  tags,
+		Author:      bm.Author,
+		Description: bm.Excerpt,
+		Content:     bm.Content,
+		Published:   published,
+		Extras: map[string]string{
+			"shiori_id": strconv.FormatInt(bm.ID, 10),
+		},
+	}
+}
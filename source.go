@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Item is the normalized shape every Source produces, so syncSource doesn't
+// need to know whether a bookmark came from a Hatena RSS feed or a Shiori
+// instance.
+type Item struct {
+	Title       string
+	Link        string
+	Image       string
+	Categories  []string
+	Author      string
+	Description string
+	Content     string
+	Published   time.Time
+	Extras      map[string]string
+}
+
+// Source fetches the current set of bookmarks from one backend.
+type Source interface {
+	// Name identifies the backend (e.g. "hatena", "shiori"); it's folded
+	// into the pebble dedup key, so each source keeps its own sync
+	// records. An item whose link another source already added to the
+	// same deck is still not added twice: syncSource's FindNotes check on
+	// the link marker finds the existing note and records it under this
+	// source's key. The same link synced into different decks gets one
+	// card per deck.
+	Name() string
+	Items(ctx context.Context) ([]Item, error)
+}
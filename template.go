@@ -0,0 +1,156 @@
+package main
+
+import (
+	_ "embed"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"golang.org/x/xerrors"
+)
+
+//go:embed template_default.lua
+var defaultTemplateSrc string
+
+// templateItem is what gets handed to render_front/render_back as the Lua
+// `item` table, so deck styling lives entirely in -template script.lua
+// instead of requiring a Go recompile.
+type templateItem struct {
+	Title         string
+	Link          string
+	ImageURL      string
+	Categories    []string
+	Author        string
+	Description   string
+	Content       string
+	Published     time.Time
+	BookmarkCount string
+}
+
+// cardTemplate wraps a gopher-lua VM with render_front/render_back already
+// defined, so the chunk is parsed and compiled once and simply invoked once
+// per feed item rather than reloaded on every card.
+type cardTemplate struct {
+	L *lua.LState
+}
+
+// loadCardTemplate compiles the Lua script at path (or the embedded default
+// when path is empty) and evaluates it once to register render_front and
+// render_back as globals.
+func loadCardTemplate(path string) (*cardTemplate, error) {
+	src := defaultTemplateSrc
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Errorf("loadCardTemplate ReadFile: %w", err)
+		}
+		src = string(b)
+	}
+
+	L := lua.NewState()
+	registerLuaHelpers(L)
+
+	if err := L.DoString(src); err != nil {
+		L.Close()
+		return nil, xerrors.Errorf("loadCardTemplate DoString: %w", err)
+	}
+
+	for _, fn := range []string{"render_front", "render_back"} {
+		if _, ok := L.GetGlobal(fn).(*lua.LFunction); !ok {
+			L.Close()
+			return nil, xerrors.Errorf("loadCardTemplate: template does not define %s", fn)
+		}
+	}
+
+	return &cardTemplate{L: L}, nil
+}
+
+// Close releases the underlying Lua VM.
+func (t *cardTemplate) Close() {
+	t.L.Close()
+}
+
+// RenderFront calls the script's render_front(item).
+func (t *cardTemplate) RenderFront(item *templateItem) (string, error) {
+	return t.call("render_front", item)
+}
+
+// RenderBack calls the script's render_back(item).
+func (t *cardTemplate) RenderBack(item *templateItem) (string, error) {
+	return t.call("render_back", item)
+}
+
+func (t *cardTemplate) call(fn string, item *templateItem) (string, error) {
+	L := t.L
+
+	if err := L.CallByParam(lua.P{
+		Fn:      L.GetGlobal(fn),
+		NRet:    1,
+		Protect: true,
+	}, itemToLua(L, item)); err != nil {
+		return "", xerrors.Errorf("lua %s: %w", fn, err)
+	}
+	defer L.Pop(1)
+
+	return lua.LVAsString(L.Get(-1)), nil
+}
+
+func itemToLua(L *lua.LState, item *templateItem) *lua.LTable {
+	tbl := L.NewTable()
+	tbl.RawSetString("title", lua.LString(item.Title))
+	tbl.RawSetString("link", lua.LString(item.Link))
+	tbl.RawSetString("image_url", lua.LString(item.ImageURL))
+	tbl.RawSetString("author", lua.LString(item.Author))
+	tbl.RawSetString("description", lua.LString(item.Description))
+	tbl.RawSetString("content", lua.LString(item.Content))
+	tbl.RawSetString("published", lua.LString(item.Published.Format(time.RFC3339)))
+	tbl.RawSetString("bookmark_count", lua.LString(item.BookmarkCount))
+
+	categories := L.NewTable()
+	for _, c := range item.Categories {
+		categories.Append(lua.LString(c))
+	}
+	tbl.RawSetString("categories", categories)
+
+	return tbl
+}
+
+// registerLuaHelpers exposes the few Go-side helpers a card template needs
+// that Lua's stdlib doesn't give you for free: HTML escaping, joining a
+// table of strings (Lua tables aren't []string), and formatting an RFC3339
+// timestamp with a Go time layout.
+func registerLuaHelpers(L *lua.LState) {
+	L.SetGlobal("html_escape", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(html.EscapeString(L.CheckString(1))))
+		return 1
+	}))
+
+	L.SetGlobal("join", L.NewFunction(func(L *lua.LState) int {
+		tbl := L.CheckTable(1)
+		sep := L.OptString(2, "")
+
+		parts := make([]string, 0, tbl.Len())
+		tbl.ForEach(func(_, v lua.LValue) {
+			parts = append(parts, v.String())
+		})
+
+		L.Push(lua.LString(strings.Join(parts, sep)))
+		return 1
+	}))
+
+	L.SetGlobal("format_time", L.NewFunction(func(L *lua.LState) int {
+		rfc3339 := L.CheckString(1)
+		layout := L.CheckString(2)
+
+		t, err := time.Parse(time.RFC3339, rfc3339)
+		if err != nil {
+			L.RaiseError("format_time: %v", err)
+			return 0
+		}
+
+		L.Push(lua.LString(t.In(time.Local).Format(layout)))
+		return 1
+	}))
+}